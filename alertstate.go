@@ -0,0 +1,179 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+)
+
+// alertStateSchema 按 (check_name, check_date) 持久化每个 check 当天的告警状态，
+// 使得(a) 相同的 status/升级阶段不会重复刷屏，(b) 进程重启不会丢失已经升级
+// 或已经通知过的事实，(c) 恢复时能够准确判断是否需要发一条"已恢复"通知。
+const alertStateSchema = `
+CREATE TABLE IF NOT EXISTS monitor_alert_state (
+    check_name        TEXT NOT NULL,
+    check_date        DATE NOT NULL,
+    status            TEXT NOT NULL,
+    stage             INT NOT NULL DEFAULT 0,
+    first_seen_at     TIMESTAMPTZ NOT NULL,
+    last_notified_at  TIMESTAMPTZ,
+    last_notified_detail TEXT NOT NULL DEFAULT '',
+    resolved          BOOLEAN NOT NULL DEFAULT false,
+    PRIMARY KEY (check_name, check_date)
+);
+`
+
+// ensureAlertStateTable 确保告警状态表存在。
+func ensureAlertStateTable(db *sql.DB) error {
+	_, err := db.Exec(alertStateSchema)
+	return err
+}
+
+// alertState 是某个 check 在某一天的告警状态快照。lastNotifiedDetail 记录
+// 上一次实际发出通知时的正文（由 pending_count 等具体数值渲染而来），用来
+// 判断"同一个 status 下数值是否已经变化"，而不是只看粗粒度的 status 有没有变。
+type alertState struct {
+	status             ruleStatus
+	stage              int
+	firstSeenAt        time.Time
+	lastNotifiedAt     sql.NullTime
+	lastNotifiedDetail string
+	resolved           bool
+}
+
+// loadAlertState 读取某个 check 当天的状态；不存在时返回 (nil, nil)。
+func loadAlertState(db *sql.DB, checkName, checkDate string) (*alertState, error) {
+	var s alertState
+	var status string
+	err := db.QueryRow(
+		`SELECT status, stage, first_seen_at, last_notified_at, last_notified_detail, resolved
+		 FROM monitor_alert_state WHERE check_name = $1 AND check_date = $2`,
+		checkName, checkDate,
+	).Scan(&status, &s.stage, &s.firstSeenAt, &s.lastNotifiedAt, &s.lastNotifiedDetail, &s.resolved)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.status = ruleStatus(status)
+	return &s, nil
+}
+
+// upsertAlertState 写入/更新某个 check 当天的状态。
+func upsertAlertState(db *sql.DB, checkName, checkDate string, s alertState) error {
+	_, err := db.Exec(`
+		INSERT INTO monitor_alert_state (check_name, check_date, status, stage, first_seen_at, last_notified_at, last_notified_detail, resolved)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (check_name, check_date) DO UPDATE SET
+			status = EXCLUDED.status,
+			stage = EXCLUDED.stage,
+			last_notified_at = EXCLUDED.last_notified_at,
+			last_notified_detail = EXCLUDED.last_notified_detail,
+			resolved = EXCLUDED.resolved
+	`, checkName, checkDate, string(s.status), s.stage, s.firstSeenAt, s.lastNotifiedAt, s.lastNotifiedDetail, s.resolved)
+	return err
+}
+
+// escalate 把基础级别按 stage 升级：每升一级严重一档，封顶 P1。
+func escalate(base Severity, stage int) Severity {
+	order := []Severity{SeverityP3, SeverityP2, SeverityP1}
+	idx := 0
+	for i, s := range order {
+		if s == base {
+			idx = i
+			break
+		}
+	}
+	idx += stage
+	if idx >= len(order) {
+		idx = len(order) - 1
+	}
+	return order[idx]
+}
+
+// baseSeverity 把规则求值的 status 映射为基础告警级别：critical 直接比
+// check 配置的 severity 高一档，warn 则使用 check 配置的 severity 本身。
+func baseSeverity(check CheckConfig, status ruleStatus) Severity {
+	sev := Severity(check.Severity)
+	if status == statusCritical {
+		return escalate(sev, 1)
+	}
+	return sev
+}
+
+// evaluateAndNotify 是告警状态机的核心：结合持久化状态决定是否抑制、
+// 是否升级、是否恢复，并按需调用 sendAlert。detail 是规则模板渲染出的
+// 正文。返回当天这个 check 是否已经完成（供调度器决定是否停止复查）。
+func evaluateAndNotify(db *sql.DB, check CheckConfig, status ruleStatus, detail string, now time.Time) bool {
+	checkDate := now.Format("2006-01-02")
+
+	state, err := loadAlertState(db, check.Name, checkDate)
+	if err != nil {
+		log.Printf("[%s] 读取告警状态失败：%v", check.Name, err)
+		return false
+	}
+
+	if status == statusOK {
+		if state != nil && !state.resolved && state.status != statusOK {
+			sendAlert(Severity(check.Severity), check.Name, check.Name+" 已恢复："+detail)
+			state.status = statusOK
+			state.resolved = true
+			state.lastNotifiedAt = sql.NullTime{Time: now, Valid: true}
+			state.lastNotifiedDetail = detail
+			if err := upsertAlertState(db, check.Name, checkDate, *state); err != nil {
+				log.Printf("[%s] 写入告警状态失败：%v", check.Name, err)
+			}
+		}
+		return true
+	}
+
+	firstSeenAt := now
+	stage := 0
+	var lastNotifiedAt sql.NullTime
+	lastNotifiedDetail := ""
+	if state != nil {
+		firstSeenAt = state.firstSeenAt
+		stage = state.stage
+		lastNotifiedAt = state.lastNotifiedAt
+		lastNotifiedDetail = state.lastNotifiedDetail
+	}
+
+	escalateAfter, _ := check.escalateAfter() // LoadConfig 已校验，不会出错
+	if escalateAfter > 0 {
+		if wantStage := int(now.Sub(firstSeenAt) / escalateAfter); wantStage > stage {
+			stage = wantStage
+		}
+	}
+
+	// 升级或者 status/detail（比如 pending_count 变化）发生了变化都算一次新的
+	// 转变；升级直接通知，status/detail 的变化仍然受 silence_window 限制，
+	// 避免一个持续处于同一 status 但数值在反复小幅波动的 check 刷屏。
+	shouldNotify := state == nil || stage > state.stage
+	if !shouldNotify && (state.status != status || lastNotifiedDetail != detail) {
+		silenceWindow, _ := check.silenceWindow()
+		shouldNotify = silenceWindow == 0 || !lastNotifiedAt.Valid || now.Sub(lastNotifiedAt.Time) >= silenceWindow
+	}
+
+	if shouldNotify {
+		severity := escalate(baseSeverity(check, status), stage)
+		sendAlert(severity, check.Name, detail)
+		lastNotifiedAt = sql.NullTime{Time: now, Valid: true}
+		lastNotifiedDetail = detail
+	}
+
+	newState := alertState{
+		status:             status,
+		stage:              stage,
+		firstSeenAt:        firstSeenAt,
+		lastNotifiedAt:     lastNotifiedAt,
+		lastNotifiedDetail: lastNotifiedDetail,
+		resolved:           false,
+	}
+	if err := upsertAlertState(db, check.Name, checkDate, newState); err != nil {
+		log.Printf("[%s] 写入告警状态失败：%v", check.Name, err)
+	}
+
+	return false
+}
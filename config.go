@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"gopkg.in/yaml.v3"
+)
+
+// CheckConfig 是一条数据驱动的规则：何时开始、多久复查一次、何时放弃、
+// 用什么 SQL 取数、如何判定 ok/warn/critical，以及未通过时如何组织告警内容。
+// 新增规则只需要编辑配置文件（并发送 SIGHUP）而不需要重新编译。
+type CheckConfig struct {
+	Name            string  `yaml:"name"`
+	Schedule        string  `yaml:"schedule"`         // 每天开始监控的 cron 表达式，如 "0 11 * * *"
+	RecheckInterval string  `yaml:"recheck_interval"` // 复查间隔，time.ParseDuration 格式，如 "30m"
+	StopAfter       string  `yaml:"stop_after"`       // 从 Schedule 触发起，超过该时长仍未通过则放弃，如 "6h"
+	Query           string  `yaml:"query"`            // SQL 查询，只取第一行；可使用 :today、:now、:threshold 具名参数
+	LagQuery        string  `yaml:"lag_query"`        // 可选，返回单个时间戳列，用于暴露 *_check_lag_seconds 指标
+	Threshold       float64 `yaml:"threshold"`        // 绑定到查询里的 :threshold，以及 Eval 表达式里的 threshold
+	Eval            string  `yaml:"eval"`             // expr-lang/expr 表达式，基于 row/now/threshold 求值为 "ok"/"warn"/"critical"
+	Template        string  `yaml:"template"`         // text/template 模板，{{.}} 是查询返回的那一行（map[string]any）
+	Severity        string  `yaml:"severity"`         // 基础告警级别（eval 结果为 warn 时使用），默认 P2
+	EscalateAfter   string  `yaml:"escalate_after"`   // 未通过状态持续超过该时长，severity 升级一级并重新通知；为空表示不升级
+	SilenceWindow   string  `yaml:"silence_window"`   // 同一升级阶段内，至少间隔该时长才重复通知一次；为空表示不静默
+}
+
+// NotifierEntryConfig 描述一个已配置的告警通道：用哪种后端、连接参数，
+// 以及只接收哪些级别的告警（为空表示全部接收）。
+type NotifierEntryConfig struct {
+	Name       string   `yaml:"name"`
+	Type       string   `yaml:"type"` // dingtalk | wecom | slack | webhook
+	URL        string   `yaml:"url"`
+	Secret     string   `yaml:"secret"`     // 钉钉加签机器人的密钥，可选
+	AtMobiles  []string `yaml:"at_mobiles"` // 钉钉/企业微信 @ 指定手机号
+	AtAll      bool     `yaml:"at_all"`
+	Severities []string `yaml:"severities"` // 只接收这些级别；为空表示全部接收
+}
+
+// Config 是监控进程的完整配置，对应命令行 -config 指向的 YAML 文件。
+type Config struct {
+	Checks    []CheckConfig         `yaml:"checks"`
+	Notifiers []NotifierEntryConfig `yaml:"notifiers"`
+}
+
+// recheckInterval 解析 RecheckInterval，供调度器内部使用。
+func (c CheckConfig) recheckInterval() (time.Duration, error) {
+	return time.ParseDuration(c.RecheckInterval)
+}
+
+// stopAfter 解析 StopAfter，供调度器内部使用。
+func (c CheckConfig) stopAfter() (time.Duration, error) {
+	return time.ParseDuration(c.StopAfter)
+}
+
+// escalateAfter 解析 EscalateAfter；未配置时返回 0，表示不启用升级。
+func (c CheckConfig) escalateAfter() (time.Duration, error) {
+	if c.EscalateAfter == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(c.EscalateAfter)
+}
+
+// silenceWindow 解析 SilenceWindow；未配置时返回 0，表示不静默。
+func (c CheckConfig) silenceWindow() (time.Duration, error) {
+	if c.SilenceWindow == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(c.SilenceWindow)
+}
+
+// validate 检查一个 CheckConfig 是否包含调度器运行所需的全部字段。
+func (c CheckConfig) validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("check 缺少 name")
+	}
+	if c.Schedule == "" {
+		return fmt.Errorf("check %q 缺少 schedule", c.Name)
+	}
+	if _, err := cron.ParseStandard(c.Schedule); err != nil {
+		return fmt.Errorf("check %q 的 schedule 非法：%w", c.Name, err)
+	}
+	if c.Query == "" {
+		return fmt.Errorf("check %q 缺少 query", c.Name)
+	}
+	if c.Eval == "" {
+		return fmt.Errorf("check %q 缺少 eval", c.Name)
+	}
+	if c.Template == "" {
+		return fmt.Errorf("check %q 缺少 template", c.Name)
+	}
+	if _, err := c.recheckInterval(); err != nil {
+		return fmt.Errorf("check %q 的 recheck_interval 非法：%w", c.Name, err)
+	}
+	if _, err := c.stopAfter(); err != nil {
+		return fmt.Errorf("check %q 的 stop_after 非法：%w", c.Name, err)
+	}
+	if _, err := c.escalateAfter(); err != nil {
+		return fmt.Errorf("check %q 的 escalate_after 非法：%w", c.Name, err)
+	}
+	if _, err := c.silenceWindow(); err != nil {
+		return fmt.Errorf("check %q 的 silence_window 非法：%w", c.Name, err)
+	}
+	return nil
+}
+
+// validate 检查一个 NotifierEntryConfig 是否包含构造对应 Notifier 所需的字段。
+func (n NotifierEntryConfig) validate() error {
+	if n.Name == "" {
+		return fmt.Errorf("notifier 缺少 name")
+	}
+	if n.Type == "" {
+		return fmt.Errorf("notifier %q 缺少 type", n.Name)
+	}
+	if n.URL == "" {
+		return fmt.Errorf("notifier %q 缺少 url", n.Name)
+	}
+	return nil
+}
+
+// LoadConfig 从 path 加载并校验监控配置。
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取配置文件失败：%w", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("解析配置文件失败：%w", err)
+	}
+	if len(cfg.Checks) == 0 {
+		return nil, fmt.Errorf("配置文件中没有声明任何 check")
+	}
+	for i, c := range cfg.Checks {
+		if c.Severity == "" {
+			cfg.Checks[i].Severity = string(SeverityP2)
+		}
+		if err := c.validate(); err != nil {
+			return nil, err
+		}
+	}
+	if len(cfg.Notifiers) == 0 {
+		return nil, fmt.Errorf("配置文件中没有声明任何 notifier")
+	}
+	for _, n := range cfg.Notifiers {
+		if err := n.validate(); err != nil {
+			return nil, err
+		}
+	}
+	return &cfg, nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// deadLetterSchema 建表语句。在一个体量不大的监控进程里，直接用与
+// 业务库相同的 PostgreSQL 连接承载死信队列，省去额外的依赖。
+const deadLetterSchema = `
+CREATE TABLE IF NOT EXISTS monitor_dead_letter (
+    id            BIGSERIAL PRIMARY KEY,
+    notifier_name TEXT NOT NULL,
+    severity      TEXT NOT NULL,
+    title         TEXT NOT NULL,
+    body          TEXT NOT NULL,
+    last_error    TEXT NOT NULL,
+    attempts      INT NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+`
+
+// ensureDeadLetterTable 确保死信表存在，调用方在使用前调用一次即可。
+func ensureDeadLetterTable(db *sql.DB) error {
+	_, err := db.Exec(deadLetterSchema)
+	return err
+}
+
+// recordDeadLetter 把某个 notifier 最终投递失败的告警持久化下来，供之后 -replay 重放。
+func recordDeadLetter(db *sql.DB, notifierName string, severity Severity, title, body, lastErr string, attempts int) error {
+	_, err := db.Exec(
+		`INSERT INTO monitor_dead_letter (notifier_name, severity, title, body, last_error, attempts) VALUES ($1, $2, $3, $4, $5, $6)`,
+		notifierName, string(severity), title, body, lastErr, attempts,
+	)
+	return err
+}
+
+// replayDeadLetters 读出死信队列中的全部记录，按记录里的 notifier_name
+// 重新投递；成功则删除，失败则更新 last_error/attempts，留给下一次 -replay。
+// 队列中引用了已从配置移除的 notifier 的记录会被跳过并记录日志。
+func replayDeadLetters(db *sql.DB, notifiers *NotifierGroup) error {
+	rows, err := db.Query(`SELECT id, notifier_name, severity, title, body FROM monitor_dead_letter ORDER BY id`)
+	if err != nil {
+		return fmt.Errorf("读取死信队列失败：%w", err)
+	}
+	defer rows.Close()
+
+	type entry struct {
+		id           int64
+		notifierName string
+		severity     string
+		title        string
+		body         string
+	}
+	var entries []entry
+	for rows.Next() {
+		var e entry
+		if err := rows.Scan(&e.id, &e.notifierName, &e.severity, &e.title, &e.body); err != nil {
+			return fmt.Errorf("扫描死信记录失败：%w", err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	log.Printf("死信队列中共有 %d 条待重放的告警", len(entries))
+
+	ctx := context.Background()
+	for _, e := range entries {
+		notifier, ok := notifiers.byName(e.notifierName)
+		if !ok {
+			log.Printf("死信记录 %d 引用的 notifier %q 已不在配置中，跳过", e.id, e.notifierName)
+			continue
+		}
+
+		if err := sendWithRetry(ctx, notifier, Severity(e.severity), e.title, e.body); err != nil {
+			log.Printf("重放死信记录 %d 仍然失败：%v", e.id, err)
+			if _, updateErr := db.Exec(
+				`UPDATE monitor_dead_letter SET last_error = $1, attempts = attempts + 1, updated_at = now() WHERE id = $2`,
+				err.Error(), e.id,
+			); updateErr != nil {
+				log.Printf("更新死信记录 %d 失败：%v", e.id, updateErr)
+			}
+			continue
+		}
+
+		log.Printf("重放死信记录 %d 成功，移出队列", e.id)
+		if _, err := db.Exec(`DELETE FROM monitor_dead_letter WHERE id = $1`, e.id); err != nil {
+			log.Printf("删除死信记录 %d 失败：%v", e.id, err)
+		}
+	}
+
+	return nil
+}
+
+// runReplay 是 -replay 子命令的入口。
+func runReplay(db *sql.DB, notifiers *NotifierGroup) {
+	if err := ensureDeadLetterTable(db); err != nil {
+		log.Fatal("初始化死信表失败：", err)
+	}
+	if err := replayDeadLetters(db, notifiers); err != nil {
+		log.Fatal("重放死信队列失败：", err)
+	}
+}
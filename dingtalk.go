@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// DingTalkNotifier 投递到钉钉自定义机器人。支持加签机器人（secret 非空时
+// 计算 timestamp/sign），以及 @指定手机号 / @所有人。
+type DingTalkNotifier struct {
+	name      string
+	url       string
+	secret    string
+	atMobiles []string
+	atAll     bool
+}
+
+// NewDingTalkNotifier 根据配置构造一个 DingTalkNotifier。
+func NewDingTalkNotifier(cfg NotifierEntryConfig) *DingTalkNotifier {
+	return &DingTalkNotifier{
+		name:      cfg.Name,
+		url:       cfg.URL,
+		secret:    cfg.Secret,
+		atMobiles: cfg.AtMobiles,
+		atAll:     cfg.AtAll,
+	}
+}
+
+func (d *DingTalkNotifier) Name() string { return d.name }
+
+type dingTalkMarkdownMessage struct {
+	MsgType  string `json:"msgtype"`
+	Markdown struct {
+		Title string `json:"title"`
+		Text  string `json:"text"`
+	} `json:"markdown"`
+	At struct {
+		AtMobiles []string `json:"atMobiles,omitempty"`
+		IsAtAll   bool     `json:"isAtAll,omitempty"`
+	} `json:"at"`
+}
+
+// dingTalkResponse 对应钉钉机器人 webhook 的响应体。即使 HTTP 状态码是
+// 200，errcode 非 0 也代表逻辑失败（签名不对、缺少关键词、触发限流等）。
+type dingTalkResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+func (d *DingTalkNotifier) Send(ctx context.Context, severity Severity, title, body string) error {
+	msg := dingTalkMarkdownMessage{MsgType: "markdown"}
+	msg.Markdown.Title = fmt.Sprintf("[%s] %s", severity, title)
+	msg.Markdown.Text = fmt.Sprintf("#### [%s] %s\n%s", severity, title, body)
+	msg.At.AtMobiles = d.atMobiles
+	msg.At.IsAtAll = d.atAll
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("序列化消息失败：%w", err)
+	}
+
+	target, err := d.signedURL()
+	if err != nil {
+		return fmt.Errorf("计算签名失败：%w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewBuffer(payload))
+	if err != nil {
+		return fmt.Errorf("构造请求失败：%w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求出错：%w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("读取响应体失败：%w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("钉钉响应非200状态：%s，body=%s", resp.Status, respBody)
+	}
+
+	var dtResp dingTalkResponse
+	if err := json.Unmarshal(respBody, &dtResp); err != nil {
+		return fmt.Errorf("解析钉钉响应体失败：%w，body=%s", err, respBody)
+	}
+	if dtResp.ErrCode != 0 {
+		return fmt.Errorf("钉钉返回逻辑错误：errcode=%d errmsg=%s", dtResp.ErrCode, dtResp.ErrMsg)
+	}
+
+	return nil
+}
+
+// signedURL 在配置了 secret 时，按钉钉加签机器人的要求拼接
+// timestamp 和 sign 查询参数；未配置 secret 时原样返回 url。
+func (d *DingTalkNotifier) signedURL() (string, error) {
+	if d.secret == "" {
+		return d.url, nil
+	}
+
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	stringToSign := timestamp + "\n" + d.secret
+
+	mac := hmac.New(sha256.New, []byte(d.secret))
+	if _, err := mac.Write([]byte(stringToSign)); err != nil {
+		return "", err
+	}
+	sign := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	parsed, err := url.Parse(d.url)
+	if err != nil {
+		return "", fmt.Errorf("解析 url 失败：%w", err)
+	}
+	q := parsed.Query()
+	q.Set("timestamp", timestamp)
+	q.Set("sign", sign)
+	parsed.RawQuery = q.Encode()
+
+	return parsed.String(), nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var metricsAddr string
+
+func init() {
+	flag.StringVar(&metricsAddr, "metrics-addr", ":9090", "Prometheus /metrics、/healthz、/readyz 监听地址")
+}
+
+var (
+	dbQuerySuccessTimestamp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "oula_payment_monitor_db_query_success_timestamp",
+		Help: "最近一次数据库查询成功时的 Unix 时间戳",
+	})
+	checkLagSeconds = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "oula_payment_monitor_check_lag_seconds",
+		Help: "check 的 lag_query 所返回时间点距当前的秒数",
+	}, []string{"check"})
+	ruleStatusGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "oula_payment_monitor_rule_status",
+		Help: "check 最近一次规则求值结果：0=ok 1=warn 2=critical",
+	}, []string{"check"})
+	alertsSentTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "oula_payment_monitor_alerts_sent_total",
+		Help: "按 notifier、severity 统计的已发送告警数",
+	}, []string{"notifier", "severity"})
+	notifierLatencySeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "oula_payment_monitor_notifier_latency_seconds",
+		Help:    "notifier 单次投递尝试的耗时分布",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"notifier"})
+	dbQueryErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "oula_payment_monitor_db_query_errors_total",
+		Help: "按 check 统计的数据库查询错误数",
+	}, []string{"check"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		dbQuerySuccessTimestamp,
+		checkLagSeconds,
+		ruleStatusGauge,
+		alertsSentTotal,
+		notifierLatencySeconds,
+		dbQueryErrorsTotal,
+	)
+}
+
+// statusToGaugeValue 把规则状态映射为 oula_payment_monitor_rule_status 的取值。
+func statusToGaugeValue(status ruleStatus) float64 {
+	switch status {
+	case statusWarn:
+		return 1
+	case statusCritical:
+		return 2
+	default:
+		return 0
+	}
+}
+
+var (
+	healthMu             sync.Mutex
+	lastCheckCompletedAt time.Time
+)
+
+// markCheckCompleted 记录某次 check 完整跑完（无论结果是否告警）的时间，
+// /readyz 用它判断今天是否已经完成过至少一轮检查。
+func markCheckCompleted(now time.Time) {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	lastCheckCompletedAt = now
+}
+
+func hasCompletedCheckToday(now time.Time) bool {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	if lastCheckCompletedAt.IsZero() {
+		return false
+	}
+	y1, m1, d1 := lastCheckCompletedAt.Date()
+	y2, m2, d2 := now.Date()
+	return y1 == y2 && m1 == m2 && d1 == d2
+}
+
+// startMetricsServer 启动一个暴露 /metrics、/healthz、/readyz 的 HTTP 服务，
+// 使监控进程自身的健康状况也能被外部告警系统观测到。
+func startMetricsServer(db *sql.DB) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+		defer cancel()
+		if err := db.PingContext(ctx); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("db ping failed: " + err.Error()))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	})
+
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if !hasCompletedCheckToday(time.Now()) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("no check cycle completed today yet"))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	})
+
+	srv := &http.Server{Addr: metricsAddr, Handler: mux}
+	go func() {
+		log.Printf("metrics/health 服务监听于 %s", metricsAddr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("metrics/health 服务退出：%v", err)
+		}
+	}()
+	return srv
+}
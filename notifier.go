@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// Severity 是告警的严重级别，决定消息路由给哪些 notifier。
+type Severity string
+
+const (
+	SeverityP1 Severity = "P1" // 紧急，例如持续较长时间仍未完成打款
+	SeverityP2 Severity = "P2" // 常规告警，默认级别
+	SeverityP3 Severity = "P3" // 仅供观察，例如结算延迟
+)
+
+// Notifier 是告警投递后端的统一接口。DingTalk、企业微信、Slack、
+// 通用 Webhook 都实现这个接口，sendAlert 不再关心具体协议细节。
+type Notifier interface {
+	Name() string
+	Send(ctx context.Context, severity Severity, title, body string) error
+}
+
+// notifierEntry 把一个 Notifier 和它订阅的级别绑在一起。
+type notifierEntry struct {
+	notifier   Notifier
+	severities map[Severity]bool // 为空表示接收全部级别
+}
+
+func (e notifierEntry) accepts(severity Severity) bool {
+	if len(e.severities) == 0 {
+		return true
+	}
+	return e.severities[severity]
+}
+
+// NotifierGroup 管理所有已配置的 notifier，并按级别过滤后扇出。
+type NotifierGroup struct {
+	entries []notifierEntry
+}
+
+// NewNotifierGroup 依据配置构建各个 Notifier 并组装成一个 NotifierGroup。
+func NewNotifierGroup(cfgs []NotifierEntryConfig) (*NotifierGroup, error) {
+	group := &NotifierGroup{}
+	for _, cfg := range cfgs {
+		notifier, err := buildNotifier(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("构造 notifier %q 失败：%w", cfg.Name, err)
+		}
+
+		severities := make(map[Severity]bool, len(cfg.Severities))
+		for _, s := range cfg.Severities {
+			severities[Severity(s)] = true
+		}
+
+		group.entries = append(group.entries, notifierEntry{notifier: notifier, severities: severities})
+	}
+	return group, nil
+}
+
+// buildNotifier 根据 type 字段构造对应的 Notifier 实现。
+func buildNotifier(cfg NotifierEntryConfig) (Notifier, error) {
+	switch cfg.Type {
+	case "dingtalk":
+		return NewDingTalkNotifier(cfg), nil
+	case "wecom":
+		return NewWeComNotifier(cfg), nil
+	case "slack":
+		return NewSlackNotifier(cfg), nil
+	case "webhook":
+		return NewWebhookNotifier(cfg), nil
+	default:
+		return nil, fmt.Errorf("未知的 notifier 类型：%q", cfg.Type)
+	}
+}
+
+// notifierFailure 记录一次扇出中，某个 notifier 最终投递失败的结果。
+type notifierFailure struct {
+	notifierName string
+	err          error
+}
+
+// Dispatch 把一条告警发给所有订阅了该 severity 的 notifier，每个 notifier
+// 独立重试。返回最终仍然失败的 notifier 列表，供调用方写入死信队列。
+func (g *NotifierGroup) Dispatch(ctx context.Context, severity Severity, title, body string) []notifierFailure {
+	var failures []notifierFailure
+	for _, entry := range g.entries {
+		if !entry.accepts(severity) {
+			continue
+		}
+		if err := sendWithRetry(ctx, entry.notifier, severity, title, body); err != nil {
+			log.Printf("notifier %q 最终投递失败：%v", entry.notifier.Name(), err)
+			failures = append(failures, notifierFailure{notifierName: entry.notifier.Name(), err: err})
+			continue
+		}
+		log.Printf("notifier %q 投递成功", entry.notifier.Name())
+	}
+	return failures
+}
+
+// All 返回 group 中全部 notifier，供 -notifier-test 这类不区分级别的场景使用。
+func (g *NotifierGroup) All() []Notifier {
+	notifiers := make([]Notifier, 0, len(g.entries))
+	for _, entry := range g.entries {
+		notifiers = append(notifiers, entry.notifier)
+	}
+	return notifiers
+}
+
+// byName 按名称查找一个已配置的 Notifier，供死信重放使用。
+func (g *NotifierGroup) byName(name string) (Notifier, bool) {
+	for _, entry := range g.entries {
+		if entry.notifier.Name() == name {
+			return entry.notifier, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"database/sql"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+var (
+	notifiersMu sync.RWMutex
+	notifiers   *NotifierGroup
+)
+
+// setNotifiers 原子地替换当前生效的 notifier 配置。
+func setNotifiers(n *NotifierGroup) {
+	notifiersMu.Lock()
+	defer notifiersMu.Unlock()
+	notifiers = n
+}
+
+// getNotifiers 读取当前生效的 notifier 配置。
+func getNotifiers() *NotifierGroup {
+	notifiersMu.RLock()
+	defer notifiersMu.RUnlock()
+	return notifiers
+}
+
+// Reloader 持有当前生效的 scheduler，使配置可以在不重启进程的情况下
+// 通过 SIGHUP 热更新：新增/修改规则只需要编辑配置文件然后发个信号。
+type Reloader struct {
+	db         *sql.DB
+	configPath string
+
+	mu        sync.Mutex
+	scheduler *Scheduler
+}
+
+// NewReloader 构造一个 Reloader，调用方随后应立即调用一次 Reload。
+func NewReloader(db *sql.DB, configPath string) *Reloader {
+	return &Reloader{db: db, configPath: configPath}
+}
+
+// Reload 重新加载配置文件，重建 notifier 和 scheduler。旧 scheduler 必须
+// 先完全停止，新 scheduler 才能 Start，否则会出现新旧两套调度同时往同一个
+// check 发告警的窗口；整个过程持有 r.mu，防止并发 Reload/Stop 互相交叉。
+// 新 scheduler 的 Start 会对每个仍处于今天监控窗口内的 check 立即续跑一次，
+// 所以 reload 不会丢掉当天剩余的监控——不需要等到明天同一时间的 schedule。
+func (r *Reloader) Reload() error {
+	cfg, err := LoadConfig(r.configPath)
+	if err != nil {
+		return err
+	}
+
+	newNotifiers, err := NewNotifierGroup(cfg.Notifiers)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.scheduler != nil {
+		r.scheduler.Stop()
+		r.scheduler = nil
+	}
+
+	newScheduler := NewScheduler(r.db, cfg.Checks)
+	if err := newScheduler.Start(); err != nil {
+		return err
+	}
+
+	setNotifiers(newNotifiers)
+	r.scheduler = newScheduler
+
+	log.Printf("配置已重新加载：%d 个 check，%d 个 notifier", len(cfg.Checks), len(cfg.Notifiers))
+	return nil
+}
+
+// Stop 停止当前生效的 scheduler。
+func (r *Reloader) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.scheduler != nil {
+		r.scheduler.Stop()
+	}
+}
+
+// watchSIGHUP 监听 SIGHUP，收到时调用 Reload；失败时记录日志并保留旧配置继续运行。
+func watchSIGHUP(r *Reloader) {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	go func() {
+		for range sighup {
+			log.Println("收到 SIGHUP，重新加载配置...")
+			if err := r.Reload(); err != nil {
+				log.Printf("重新加载配置失败，继续使用旧配置：%v", err)
+			}
+		}
+	}()
+}
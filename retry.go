@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+)
+
+var maxSendAttempts int
+
+func init() {
+	flag.IntVar(&maxSendAttempts, "max-send-attempts", 4, "每个 notifier 发送一条告警的最大尝试次数（含首次发送）")
+}
+
+// sendWithRetry 调用 n.Send，失败时按指数退避重试，直到成功或
+// 达到 maxSendAttempts 次。返回最后一次的错误。
+func sendWithRetry(ctx context.Context, n Notifier, severity Severity, title, body string) error {
+	var lastErr error
+	for attempt := 1; attempt <= maxSendAttempts; attempt++ {
+		start := time.Now()
+		err := n.Send(ctx, severity, title, body)
+		notifierLatencySeconds.WithLabelValues(n.Name()).Observe(time.Since(start).Seconds())
+
+		if err != nil {
+			lastErr = err
+			log.Printf("notifier %q 发送失败（第 %d/%d 次）：%v", n.Name(), attempt, maxSendAttempts, err)
+			if attempt < maxSendAttempts {
+				time.Sleep(backoff(attempt))
+			}
+			continue
+		}
+
+		alertsSentTotal.WithLabelValues(n.Name(), string(severity)).Inc()
+		return nil
+	}
+
+	return fmt.Errorf("尝试 %d 次后仍然失败：%w", maxSendAttempts, lastErr)
+}
+
+// backoff 计算第 attempt 次重试前的等待时间：1s, 2s, 4s, 8s... 封顶 8s，并加入抖动，
+// 避免多个告警同时重试时对下游服务形成突发流量。
+func backoff(attempt int) time.Duration {
+	base := 1 << (attempt - 1) // 1, 2, 4, 8, ...
+	if base > 8 {
+		base = 8
+	}
+	jitter := time.Duration(rand.Int63n(int64(time.Second)))
+	return time.Duration(base)*time.Second + jitter
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/expr-lang/expr"
+)
+
+// ruleStatus 是规则求值后的结果，决定告警是否触发以及触发时的基础严重程度。
+type ruleStatus string
+
+const (
+	statusOK       ruleStatus = "ok"
+	statusWarn     ruleStatus = "warn"
+	statusCritical ruleStatus = "critical"
+)
+
+// bindNamedParams 把查询里出现的 :today、:now、:threshold 具名参数替换成
+// PostgreSQL 的位置参数，只为实际出现过的参数分配 $N 和对应的值——lib/pq
+// 在传入任何参数时都会走扩展协议，PostgreSQL 按语句里最大的 $N 推断参数
+// 个数，多传会被拒绝（"bind message supplies N parameters, but prepared
+// statement requires M"）。这三个是目前规则引擎用到的全部具名参数，还
+// 不需要为此引入完整的具名参数库。
+func bindNamedParams(query string, threshold float64, now time.Time) (string, []any) {
+	named := []struct {
+		placeholder string
+		value       any
+	}{
+		{":today", now.Format("2006-01-02")},
+		{":now", now},
+		{":threshold", threshold},
+	}
+
+	var args []any
+	for _, n := range named {
+		if !strings.Contains(query, n.placeholder) {
+			continue
+		}
+		args = append(args, n.value)
+		query = strings.ReplaceAll(query, n.placeholder, fmt.Sprintf("$%d", len(args)))
+	}
+
+	return query, args
+}
+
+// runRule 执行一条规则的 Query，把返回的第一行整理成 map[string]any，
+// 再用 Eval 表达式求值得到 ruleStatus。
+func runRule(db *sql.DB, check CheckConfig, now time.Time) (ruleStatus, map[string]any, error) {
+	query, args := bindNamedParams(check.Query, check.Threshold, now)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return "", nil, fmt.Errorf("执行查询失败：%w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return "", nil, fmt.Errorf("查询没有返回任何行")
+	}
+
+	row, err := scanRow(rows)
+	if err != nil {
+		return "", nil, fmt.Errorf("读取查询结果失败：%w", err)
+	}
+
+	status, err := evalRule(check.Eval, row, check.Threshold, now)
+	if err != nil {
+		return "", nil, fmt.Errorf("求值 eval 表达式失败：%w", err)
+	}
+
+	return status, row, nil
+}
+
+// scanRow 把 *sql.Rows 当前这一行读成 map[string]any，键是列名。
+func scanRow(rows *sql.Rows) (map[string]any, error) {
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]any, len(cols))
+	pointers := make([]any, len(cols))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	if err := rows.Scan(pointers...); err != nil {
+		return nil, err
+	}
+
+	row := make(map[string]any, len(cols))
+	for i, col := range cols {
+		row[col] = values[i]
+	}
+	return row, nil
+}
+
+// evalRule 用 expr-lang/expr 求值 exprStr，表达式可以访问 row（查询结果那一行）、
+// now（当前时间）和 threshold（规则配置的阈值），必须返回 "ok"/"warn"/"critical" 之一。
+func evalRule(exprStr string, row map[string]any, threshold float64, now time.Time) (ruleStatus, error) {
+	env := map[string]any{
+		"row":       row,
+		"now":       now,
+		"threshold": threshold,
+	}
+
+	program, err := expr.Compile(exprStr, expr.Env(env))
+	if err != nil {
+		return "", fmt.Errorf("编译表达式失败：%w", err)
+	}
+
+	result, err := expr.Run(program, env)
+	if err != nil {
+		return "", fmt.Errorf("执行表达式失败：%w", err)
+	}
+
+	status, ok := result.(string)
+	if !ok {
+		return "", fmt.Errorf("eval 表达式必须返回字符串，实际返回 %T", result)
+	}
+
+	switch ruleStatus(status) {
+	case statusOK, statusWarn, statusCritical:
+		return ruleStatus(status), nil
+	default:
+		return "", fmt.Errorf("eval 表达式返回了未知状态 %q，应为 ok/warn/critical 之一", status)
+	}
+}
+
+// renderTemplate 用 Go text/template 渲染告警正文，{{.}} 指向查询返回的那一行。
+func renderTemplate(tmplStr string, row map[string]any) (string, error) {
+	tmpl, err := template.New("alert").Parse(tmplStr)
+	if err != nil {
+		return "", fmt.Errorf("解析模板失败：%w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, row); err != nil {
+		return "", fmt.Errorf("渲染模板失败：%w", err)
+	}
+	return buf.String(), nil
+}
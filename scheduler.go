@@ -0,0 +1,199 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Scheduler 按各个 check 自己的 cron 表达式并发调度，取代原先单一的
+// daily-loop + 全局 isCompleted 标记，使同一进程可以同时监控多个互不
+// 干扰的 SLA。
+type Scheduler struct {
+	db     *sql.DB
+	cron   *cron.Cron
+	checks []CheckConfig
+
+	mu        sync.Mutex
+	completed map[string]bool // 按 check name 记录当天是否已完成
+
+	stopCh chan struct{} // 关闭后，正在运行的 runCheck 复查循环会立即退出
+	wg     sync.WaitGroup
+}
+
+// NewScheduler 基于配置中的 checks 构建调度器。
+func NewScheduler(db *sql.DB, checks []CheckConfig) *Scheduler {
+	return &Scheduler{
+		db:        db,
+		cron:      cron.New(),
+		checks:    checks,
+		completed: make(map[string]bool),
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Start 为每个 check 注册 cron 任务并启动调度循环，不会阻塞。如果某个
+// check 今天的监控窗口已经开始但还没结束（典型场景是 SIGHUP reload 重建了
+// scheduler），会立即续跑一次 runCheck，而不是干等到下一次 schedule 触发——
+// 否则今天剩余的监控窗口会被直接丢弃，要等到明天同一时间才恢复监控。
+func (s *Scheduler) Start() error {
+	for _, check := range s.checks {
+		check := check
+		schedule, err := cron.ParseStandard(check.Schedule)
+		if err != nil {
+			return fmt.Errorf("解析 check %q 的 schedule 失败：%w", check.Name, err)
+		}
+		if _, err := s.cron.AddFunc(check.Schedule, func() {
+			s.runCheck(check, time.Now())
+		}); err != nil {
+			return fmt.Errorf("注册 check %q 的 schedule 失败：%w", check.Name, err)
+		}
+		log.Printf("已注册 check %q，schedule=%q recheck=%q stopAfter=%q", check.Name, check.Schedule, check.RecheckInterval, check.StopAfter)
+
+		now := time.Now()
+		if s.windowActive(check, schedule, now) {
+			log.Printf("[%s] 今日监控窗口仍在进行中，立即续跑", check.Name)
+			go s.runCheck(check, schedule.Next(now.Add(-24*time.Hour)))
+		}
+	}
+	s.cron.Start()
+	return nil
+}
+
+// windowActive 判断 check 今天的监控窗口（从最近一次 schedule 触发算起，
+// 持续 stop_after 时长）此刻是否仍然有效，且当天的告警状态不是"已恢复"。
+// 当天没有状态记录（包括一次就通过、从未告警的情况）时也返回 true。Start
+// 在进程首次启动和 SIGHUP reload 时都会调用它，决定是否需要立即续跑。
+func (s *Scheduler) windowActive(check CheckConfig, schedule cron.Schedule, now time.Time) bool {
+	stopAfter, err := check.stopAfter() // LoadConfig 已校验，不会出错
+	if err != nil {
+		return false
+	}
+
+	lastFire := schedule.Next(now.Add(-24 * time.Hour))
+	if lastFire.After(now) || now.Sub(lastFire) > stopAfter {
+		return false
+	}
+
+	state, err := loadAlertState(s.db, check.Name, lastFire.Format("2006-01-02"))
+	if err != nil {
+		log.Printf("[%s] 读取告警状态失败，按需要续跑处理：%v", check.Name, err)
+		return true
+	}
+	return state == nil || !state.resolved
+}
+
+// Stop 停止调度器：先关闭 stopCh 让所有正在跑的 runCheck 复查循环尽快
+// 退出，再停止 cron 防止触发新的任务，最后等待所有 runCheck 真正返回。
+// 复查循环会监听 stopCh，所以 Stop 不必等到长达 stop_after 的监控窗口结束。
+func (s *Scheduler) Stop() {
+	close(s.stopCh)
+	<-s.cron.Stop().Done()
+	s.wg.Wait()
+}
+
+// runCheck 是某个 check 当天监控窗口的完整生命周期：立即检查一次，
+// 然后按 RecheckInterval 定时复查，直到完成、超过从 windowStart（本次
+// schedule 触发的时间）起算的 StopAfter，或调度器被 Stop。
+func (s *Scheduler) runCheck(check CheckConfig, windowStart time.Time) {
+	s.wg.Add(1)
+	defer s.wg.Done()
+
+	s.mu.Lock()
+	s.completed[check.Name] = false
+	s.mu.Unlock()
+
+	log.Printf("[%s] 进入今日监控窗口", check.Name)
+
+	interval, _ := check.recheckInterval() // LoadConfig 已校验，不会出错
+	stopAfter, _ := check.stopAfter()
+
+	deadline := windowStart.Add(stopAfter)
+
+	s.checkAndAlert(check)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopCh:
+			log.Printf("[%s] 调度器已停止，退出本轮复查", check.Name)
+			return
+		case now := <-ticker.C:
+			if s.isCompleted(check.Name) {
+				log.Printf("[%s] 已完成，停止本轮复查", check.Name)
+				return
+			}
+			if now.After(deadline) {
+				log.Printf("[%s] 超过 stop_after (%s)，放弃本轮复查", check.Name, check.StopAfter)
+				return
+			}
+			s.checkAndAlert(check)
+		}
+	}
+}
+
+func (s *Scheduler) isCompleted(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.completed[name]
+}
+
+func (s *Scheduler) setCompleted(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.completed[name] = true
+}
+
+// checkAndAlert 跑一条规则：执行 Query、用 Eval 求值出 ruleStatus、渲染
+// Template 得到告警正文，再交给告警状态机决定是否需要通知，最后据此
+// 更新完成标记。
+func (s *Scheduler) checkAndAlert(check CheckConfig) {
+	log.Printf("[%s] 执行规则查询...", check.Name)
+
+	if check.LagQuery != "" {
+		s.recordLag(check)
+	}
+
+	now := time.Now()
+	status, row, err := runRule(s.db, check, now)
+	if err != nil {
+		log.Printf("[%s] 规则执行出错：%v", check.Name, err)
+		dbQueryErrorsTotal.WithLabelValues(check.Name).Inc()
+		return
+	}
+	dbQuerySuccessTimestamp.SetToCurrentTime()
+	ruleStatusGauge.WithLabelValues(check.Name).Set(statusToGaugeValue(status))
+
+	detail, err := renderTemplate(check.Template, row)
+	if err != nil {
+		log.Printf("[%s] 渲染告警模板出错：%v", check.Name, err)
+		return
+	}
+
+	log.Printf("[%s] 规则求值结果：%s", check.Name, status)
+
+	if evaluateAndNotify(s.db, check, status, detail, now) {
+		log.Printf("[%s] 已全部通过，标记为已完成", check.Name)
+		s.setCompleted(check.Name)
+	}
+
+	markCheckCompleted(now)
+}
+
+// recordLag 执行 check 的 LagQuery（返回单个时间戳列），并把距今的秒数
+// 暴露为 oula_payment_monitor_check_lag_seconds 指标。
+func (s *Scheduler) recordLag(check CheckConfig) {
+	var ts time.Time
+	if err := s.db.QueryRow(check.LagQuery).Scan(&ts); err != nil {
+		log.Printf("[%s] lag_query 查询出错：%v", check.Name, err)
+		dbQueryErrorsTotal.WithLabelValues(check.Name).Inc()
+		return
+	}
+	checkLagSeconds.WithLabelValues(check.Name).Set(time.Since(ts).Seconds())
+}
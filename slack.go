@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// SlackNotifier 投递到 Slack 的 incoming webhook。
+type SlackNotifier struct {
+	name string
+	url  string
+}
+
+// NewSlackNotifier 根据配置构造一个 SlackNotifier。
+func NewSlackNotifier(cfg NotifierEntryConfig) *SlackNotifier {
+	return &SlackNotifier{name: cfg.Name, url: cfg.URL}
+}
+
+func (s *SlackNotifier) Name() string { return s.name }
+
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+func (s *SlackNotifier) Send(ctx context.Context, severity Severity, title, body string) error {
+	msg := slackMessage{Text: fmt.Sprintf("*[%s] %s*\n%s", severity, title, body)}
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("序列化消息失败：%w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewBuffer(payload))
+	if err != nil {
+		return fmt.Errorf("构造请求失败：%w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求出错：%w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("读取响应体失败：%w", err)
+	}
+
+	// Slack incoming webhook 成功时返回 200 加纯文本 "ok"，不是 JSON。
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Slack 响应非200状态：%s，body=%s", resp.Status, respBody)
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// WebhookNotifier 投递到一个通用的 JSON webhook，供没有专用实现的
+// 下游系统接入（比如内部的工单或 IM 网关）。
+type WebhookNotifier struct {
+	name string
+	url  string
+}
+
+// NewWebhookNotifier 根据配置构造一个 WebhookNotifier。
+func NewWebhookNotifier(cfg NotifierEntryConfig) *WebhookNotifier {
+	return &WebhookNotifier{name: cfg.Name, url: cfg.URL}
+}
+
+func (w *WebhookNotifier) Name() string { return w.name }
+
+type webhookPayload struct {
+	Severity string `json:"severity"`
+	Title    string `json:"title"`
+	Body     string `json:"body"`
+}
+
+func (w *WebhookNotifier) Send(ctx context.Context, severity Severity, title, body string) error {
+	payload, err := json.Marshal(webhookPayload{Severity: string(severity), Title: title, Body: body})
+	if err != nil {
+		return fmt.Errorf("序列化消息失败：%w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewBuffer(payload))
+	if err != nil {
+		return fmt.Errorf("构造请求失败：%w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求出错：%w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("读取响应体失败：%w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook 响应非2xx状态：%s，body=%s", resp.Status, respBody)
+	}
+
+	return nil
+}
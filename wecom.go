@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// WeComNotifier 投递到企业微信群机器人 webhook。
+type WeComNotifier struct {
+	name      string
+	url       string
+	atMobiles []string
+	atAll     bool
+}
+
+// NewWeComNotifier 根据配置构造一个 WeComNotifier。
+func NewWeComNotifier(cfg NotifierEntryConfig) *WeComNotifier {
+	return &WeComNotifier{
+		name:      cfg.Name,
+		url:       cfg.URL,
+		atMobiles: cfg.AtMobiles,
+		atAll:     cfg.AtAll,
+	}
+}
+
+func (w *WeComNotifier) Name() string { return w.name }
+
+type weComMarkdownMessage struct {
+	MsgType  string `json:"msgtype"`
+	Markdown struct {
+		Content string `json:"content"`
+	} `json:"markdown"`
+}
+
+type weComTextMessage struct {
+	MsgType string `json:"msgtype"`
+	Text    struct {
+		Content             string   `json:"content"`
+		MentionedMobileList []string `json:"mentioned_mobile_list,omitempty"`
+	} `json:"text"`
+}
+
+type weComResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+func (w *WeComNotifier) Send(ctx context.Context, severity Severity, title, body string) error {
+	// 企业微信的 markdown 消息类型不支持 @，需要额外补发一条 text 消息来 @ 相关人员。
+	markdown := weComMarkdownMessage{MsgType: "markdown"}
+	markdown.Markdown.Content = fmt.Sprintf("#### [%s] %s\n%s", severity, title, body)
+	if err := w.post(ctx, markdown); err != nil {
+		return err
+	}
+
+	if len(w.atMobiles) == 0 && !w.atAll {
+		return nil
+	}
+
+	mentions := w.atMobiles
+	if w.atAll {
+		mentions = append(append([]string{}, mentions...), "@all")
+	}
+
+	text := weComTextMessage{MsgType: "text"}
+	text.Text.Content = fmt.Sprintf("[%s] %s", severity, title)
+	text.Text.MentionedMobileList = mentions
+	return w.post(ctx, text)
+}
+
+func (w *WeComNotifier) post(ctx context.Context, message any) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("序列化消息失败：%w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewBuffer(payload))
+	if err != nil {
+		return fmt.Errorf("构造请求失败：%w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求出错：%w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("读取响应体失败：%w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("企业微信响应非200状态：%s，body=%s", resp.Status, respBody)
+	}
+
+	var wcResp weComResponse
+	if err := json.Unmarshal(respBody, &wcResp); err != nil {
+		return fmt.Errorf("解析企业微信响应体失败：%w，body=%s", err, respBody)
+	}
+	if wcResp.ErrCode != 0 {
+		return fmt.Errorf("企业微信返回逻辑错误：errcode=%d errmsg=%s", wcResp.ErrCode, wcResp.ErrMsg)
+	}
+
+	return nil
+}